@@ -0,0 +1,42 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"os/exec"
+)
+
+// CopyToClipboard copies s to the system clipboard, trying xclip and pbcopy in
+// turn before falling back to an OSC52 escape sequence, which most modern
+// terminal emulators (and SSH sessions passing it through) honour even
+// without a helper binary installed.
+func CopyToClipboard(s string) error {
+	if err := copyWith("xclip", []string{"-selection", "clipboard"}, s); err == nil {
+		return nil
+	}
+	if err := copyWith("pbcopy", nil, s); err == nil {
+		return nil
+	}
+	return copyOSC52(s)
+}
+
+// copyWith pipes s into the stdin of the named command, returning an error if
+// the command does not exist or exits non-zero.
+func copyWith(name string, args []string, s string) error {
+	path, err := exec.LookPath(name)
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command(path, args...)
+	cmd.Stdin = bytes.NewBufferString(s)
+	return cmd.Run()
+}
+
+// copyOSC52 writes an OSC52 "set clipboard" escape sequence directly to the
+// terminal, for environments (e.g. over SSH) with no clipboard helper.
+func copyOSC52(s string) error {
+	encoded := base64.StdEncoding.EncodeToString([]byte(s))
+	_, err := fmt.Print("\033]52;c;" + encoded + "\a")
+	return err
+}
@@ -0,0 +1,35 @@
+package main
+
+const (
+	Black  = `0`
+	Red    = `1`
+	Green  = `2`
+	Brown  = `3`
+	Blue   = `4`
+	Purple = `5`
+	Cyan   = `6`
+	Gray   = `7`
+)
+
+// Color returns the ANSI colour code for the given background and foreground.
+// Note Bold is usually interpeted as 'light' these days. E.g. 'light blue.'
+func Color(bg string, fg string, bold bool) string {
+	var boldstr string
+	if bold {
+		boldstr = `1`
+	} else {
+		boldstr = `0`
+	}
+	return "\033[4" + bg + `;` + boldstr + `;3` + fg + `m`
+
+}
+
+// InverseColor returns the inverse ANSI colour code, which, when sent to the terminal, will invert the current colours.
+func InverseColor() string {
+	return "\033[7m"
+}
+
+// ResetColor returns the reset ANSI colour code, which will reset the terminal colours to their default
+func ResetColor() string {
+	return "\033[0m"
+}
@@ -0,0 +1,111 @@
+package main
+
+import "strings"
+
+// fuzzyBonusConsecutive rewards runs of matched characters that follow each other directly.
+const fuzzyBonusConsecutive = 8
+
+// fuzzyBonusBoundary rewards a match immediately after a word boundary (-, _, space, /, .) or a
+// lower-to-upper camelCase transition.
+const fuzzyBonusBoundary = 6
+
+// fuzzyBonusStart rewards a match at the very start of the string.
+const fuzzyBonusStart = 4
+
+// fuzzyPenaltyGap is subtracted for each unmatched character skipped between two matches.
+const fuzzyPenaltyGap = 2
+
+// FuzzyMatch scores how well pattern matches text using Smith-Waterman-style
+// bonuses and penalties: consecutive matches, matches on word boundaries, and
+// matches at the start of the string are rewarded, gaps between matches are
+// penalized. It matches greedily, pairing each rune of pattern with its first
+// occurrence in text after the previous match, and scores only that one
+// alignment; other alignments are not tried. It returns that score, whether
+// every rune in pattern was matched in order, and the indices into text that
+// were matched (for highlighting). Matching is case-insensitive.
+func FuzzyMatch(pattern, text string) (score int, matched bool, positions []int) {
+	if pattern == "" {
+		return 0, true, nil
+	}
+
+	p := []rune(strings.ToLower(pattern))
+	t := []rune(text)
+	tl := []rune(strings.ToLower(text))
+
+	positions = make([]int, 0, len(p))
+	pi := 0
+	lastMatch := -1
+
+	for ti := 0; ti < len(tl) && pi < len(p); ti++ {
+		if tl[ti] != p[pi] {
+			continue
+		}
+
+		if lastMatch == ti-1 {
+			score += fuzzyBonusConsecutive
+		} else if lastMatch != -1 {
+			score -= fuzzyPenaltyGap * (ti - lastMatch - 1)
+		}
+
+		if ti == 0 {
+			score += fuzzyBonusStart
+		} else if isBoundary(t[ti-1]) {
+			score += fuzzyBonusBoundary
+		} else if isCamelBoundary(t[ti-1], t[ti]) {
+			score += fuzzyBonusBoundary
+		}
+
+		positions = append(positions, ti)
+		lastMatch = ti
+		pi++
+	}
+
+	return score, pi == len(p), positions
+}
+
+// isBoundary reports whether r commonly separates words in shell commands.
+func isBoundary(r rune) bool {
+	return r == '-' || r == '_' || r == ' ' || r == '/' || r == '.'
+}
+
+// isCamelBoundary reports whether prev->cur is a lower-to-upper camelCase transition.
+func isCamelBoundary(prev, cur rune) bool {
+	return prev >= 'a' && prev <= 'z' && cur >= 'A' && cur <= 'Z'
+}
+
+// fuzzyEntry pairs a candidate line of text with the heading it belongs to and
+// the command text to copy when it is selected.
+type fuzzyEntry struct {
+	Heading string
+	Display string
+	Command string
+}
+
+// fuzzyResult is a scored fuzzyEntry, ready to be sorted and rendered.
+type fuzzyResult struct {
+	fuzzyEntry
+	Score     int
+	Matched   bool
+	Positions []int
+}
+
+// FuzzyFilter scores every entry against pattern and returns only the matches,
+// sorted best-first.
+func FuzzyFilter(entries []fuzzyEntry, pattern string) []fuzzyResult {
+	results := make([]fuzzyResult, 0, len(entries))
+	for _, e := range entries {
+		score, matched, positions := FuzzyMatch(pattern, e.Display)
+		if !matched {
+			continue
+		}
+		results = append(results, fuzzyResult{fuzzyEntry: e, Score: score, Matched: matched, Positions: positions})
+	}
+
+	for i := 1; i < len(results); i++ {
+		for j := i; j > 0 && results[j].Score > results[j-1].Score; j-- {
+			results[j], results[j-1] = results[j-1], results[j]
+		}
+	}
+
+	return results
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// RunHeaderFirst pipes body through a pager with legend pinned at the top of
+// the screen (in inverse video) while the rest scrolls beneath it, mirroring
+// the sticky-header UX of fuzzy finders. It shells out to `less --header=N`,
+// which natively supports pinning its first N lines. Where less isn't
+// available, or is too old to support --header (added in less 581; Debian
+// and Ubuntu both still ship older releases missing it), it falls back to
+// printing the legend followed by the body without pinning.
+func RunHeaderFirst(legend, body string) error {
+	path, err := exec.LookPath("less")
+	if err != nil || !lessSupportsHeader(path) {
+		fmt.Print(legend + body)
+		return nil
+	}
+
+	headerLines := strings.Count(legend, "\n")
+
+	cmd := exec.Command(path, "-R", "--header="+strconv.Itoa(headerLines))
+	cmd.Stdin = strings.NewReader(legend + body)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// lessSupportsHeader probes whether the less binary at path recognizes
+// --header. less doesn't exit non-zero for an unrecognized option (it prints
+// an error and continues on to process the remaining ones), so the only
+// reliable signal is the "no header=N option" message it emits.
+func lessSupportsHeader(path string) bool {
+	cmd := exec.Command(path, "--header=0", "--version")
+	cmd.Stdin = nil
+	out, _ := cmd.CombinedOutput()
+	return !strings.Contains(strings.ToLower(string(out)), "no header")
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// JsonCommand represents a single command within a heading. This is designed
+// to be parsed from JSON, accepting either a bare string (the common case) or
+// an object carrying an optional Note and Tags, so existing cheatsheets keep
+// working unmodified.
+type JsonCommand struct {
+	Command string   `json:"command"`
+	Note    string   `json:"note,omitempty"`
+	Tags    []string `json:"tags,omitempty"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting a bare string or a
+// {"command": "...", "note": "...", "tags": [...]} object.
+func (c *JsonCommand) UnmarshalJSON(data []byte) error {
+	var command string
+	if err := json.Unmarshal(data, &command); err == nil {
+		c.Command = command
+		return nil
+	}
+
+	type jsonCommandAlias JsonCommand
+	var alias jsonCommandAlias
+	if err := json.Unmarshal(data, &alias); err != nil {
+		return err
+	}
+	*c = JsonCommand(alias)
+	return nil
+}
+
+// HasTag reports whether c is tagged with tag.
+func (c JsonCommand) HasTag(tag string) bool {
+	for _, t := range c.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// JsonHeading represents a cheatsheet heading definition. This is designed to be parsed from JSON.
+type JsonHeading struct {
+	Title       string        `json:"title"`
+	Description string        `json:"description,omitempty"`
+	Commands    []JsonCommand `json:"commands"`
+}
+
+// JsonHeadings represents a cheatsheet definition, of all headings. This is designed to be parsed from JSON.
+type JsonHeadings struct {
+	Headings []JsonHeading `json:"headings"`
+}
+
+// FilterByTag returns a copy of hs where every heading keeps only the
+// commands tagged with tag, preserving the headings themselves (and thus the
+// column layout) even when a heading ends up with no matching commands. An
+// empty tag returns hs unchanged.
+func (hs JsonHeadings) FilterByTag(tag string) JsonHeadings {
+	if tag == "" {
+		return hs
+	}
+
+	filtered := JsonHeadings{Headings: make([]JsonHeading, len(hs.Headings))}
+	for i, heading := range hs.Headings {
+		kept := heading
+		kept.Commands = nil
+		for _, command := range heading.Commands {
+			if command.HasTag(tag) {
+				kept.Commands = append(kept.Commands, command)
+			}
+		}
+		filtered.Headings[i] = kept
+	}
+	return filtered
+}
+
+// Width returns the width of this JsonHeading, which is the width of its title or its longest command, whichever is wider.
+func (h JsonHeading) Width() int {
+	max := len(h.Title)
+	for _, v := range h.Commands {
+		if len(v.Command) > max {
+			max = len(v.Command)
+		}
+	}
+	return max
+}
+
+// HeadingWidth returns the width which headings should be, which is the width of the widest heading
+func (hs JsonHeadings) HeadingWidth() int {
+	max := 0
+	for _, v := range hs.Headings {
+		if v.Width() > max {
+			max = v.Width()
+		}
+	}
+	return max
+}
+
+// wrapText soft-wraps s into lines no longer than width, breaking on the last
+// space within the limit where possible and hard-breaking otherwise. A
+// non-positive width disables wrapping.
+func wrapText(s string, width int) []string {
+	if width <= 0 || len(s) <= width {
+		return []string{s}
+	}
+
+	var lines []string
+	for len(s) > width {
+		breakAt := strings.LastIndex(s[:width+1], " ")
+		if breakAt <= 0 {
+			breakAt = width
+		}
+		lines = append(lines, strings.TrimRight(s[:breakAt], " "))
+		s = strings.TrimLeft(s[breakAt:], " ")
+	}
+	lines = append(lines, s)
+	return lines
+}
+
+// WrappedCommandLines flattens h's commands into display lines, soft-wrapping
+// any command that exceeds width onto continuation lines.
+func (h JsonHeading) WrappedCommandLines(width int) []string {
+	var lines []string
+	for _, command := range h.Commands {
+		lines = append(lines, wrapText(command.Command, width)...)
+	}
+	return lines
+}
+
+// CommandsHeight is the height which commands should be, which is the number of
+// wrapped command lines in the heading with the most, at the given column width.
+func (hs JsonHeadings) CommandsHeight(width int) int {
+	max := 0
+	for _, v := range hs.Headings {
+		if n := len(v.WrappedCommandLines(width)); n > max {
+			max = n
+		}
+	}
+	return max
+}
+
+// RowHasCommands returns whether a given row has any wrapped command lines.
+// This is used to not display blank lines, if all the headings in a given row have fewer than the tallest row.
+func (hs JsonHeadings) RowHasCommands(start, end, row, width int) bool {
+	for i := start; i < end; i++ {
+		if len(hs.Headings[i].WrappedCommandLines(width)) >= (row + 1) {
+			return true
+		}
+	}
+	return false
+}
+
+// padTo pads or truncates s to exactly width bytes, one column per byte as for
+// ASCII text. A non-positive width yields an empty string.
+func padTo(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	if len(s) >= width {
+		return s[:width]
+	}
+	return s + strings.Repeat(" ", width-len(s))
+}
+
+// PrintHeadings returns the heading string to be printed to the console, including ANSI color codes
+func (hs *JsonHeadings) PrintHeadings(start, end, width int) string {
+	if end > len(hs.Headings) {
+		end = len(hs.Headings)
+	}
+	contentWidth := width - 1
+	if contentWidth < 1 {
+		contentWidth = 1
+	}
+
+	var s string
+	for i := start; i != end; i++ {
+		heading := hs.Headings[i]
+		s += InverseColor()
+		s += padTo(heading.Title, width-1)
+		s += ResetColor()
+		s += ` `
+	}
+
+	commandsHeight := hs.CommandsHeight(contentWidth)
+	for j := 0; j != commandsHeight; j++ {
+		if !hs.RowHasCommands(start, end, j, contentWidth) {
+			continue
+		}
+		s += "\n"
+		for i := start; i < end; i++ {
+			lines := hs.Headings[i].WrappedCommandLines(contentWidth)
+			if len(lines) <= j {
+				s += strings.Repeat(" ", width)
+				continue
+			}
+			s += padTo(lines[j], width)
+		}
+	}
+
+	return s
+}
+
+// PrintString returns a string for printing, of the headings, including ANSI color codes, formatted to the given width.
+func (hs *JsonHeadings) PrintString(width int) string {
+	var s string
+
+	headingWidth := hs.HeadingWidth() + 1
+	if headingWidth > width {
+		headingWidth = width
+	}
+	if headingWidth < 1 {
+		headingWidth = 1
+	}
+
+	headingsPerLine := width / headingWidth // +1 because headings are separated
+	if headingsPerLine < 1 {
+		headingsPerLine = 1
+	}
+	for i := 0; i < len(hs.Headings); i += headingsPerLine {
+		s += hs.PrintHeadings(i, i+headingsPerLine, headingWidth) + "\n"
+	}
+	return s
+}
+
+// Legend renders the pinned header block shown above the scrolling body in
+// --header-first mode: the program name, followed by each heading's title
+// and description (headings without a description are omitted).
+func (hs *JsonHeadings) Legend(programName string) string {
+	var s string
+	s += InverseColor() + programName + ResetColor() + "\n"
+	for _, heading := range hs.Headings {
+		if heading.Description == "" {
+			continue
+		}
+		s += InverseColor() + heading.Title + ": " + heading.Description + ResetColor() + "\n"
+	}
+	return s
+}
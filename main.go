@@ -2,188 +2,87 @@ package main
 
 import (
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
-	"os/exec"
 	"strconv"
 	"strings"
 )
 
-const (
-	Black  = `0`
-	Red    = `1`
-	Green  = `2`
-	Brown  = `3`
-	Blue   = `4`
-	Purple = `5`
-	Cyan   = `6`
-	Gray   = `7`
-)
-
-// Color returns the ANSI colour code for the given background and foreground.
-// Note Bold is usually interpeted as 'light' these days. E.g. 'light blue.'
-func Color(bg string, fg string, bold bool) string {
-	var boldstr string
-	if bold {
-		boldstr = `1`
-	} else {
-		boldstr = `0`
-	}
-	return "\033[4" + bg + `;` + boldstr + `;3` + fg + `m`
-
-}
-
-// InverseColor returns the inverse ANSI colour code, which, when sent to the terminal, will invert the current colours.
-func InverseColor() string {
-	return "\033[7m"
-}
-
-// ResetColor returns the reset ANSI colour code, which will reset the terminal colours to their default
-func ResetColor() string {
-	return "\033[0m"
-}
-
-// TerminalSize returns the width and height of the tty, respectively
-func TerminalSize() (int, int, error) {
-	cmd := exec.Command("stty", "size")
-	cmd.Stdin = os.Stdin
-	out, err := cmd.Output()
-	if err != nil {
-		return 0, 0, err
-	}
-	outarr := strings.Split(string(out), " ")
-	if len(outarr) != 2 {
-		return 0, 0, errors.New("Terminal size split error, stty size return unexpected: " + string(out))
-	}
-
-	height, err := strconv.Atoi(outarr[0])
-	if err != nil {
-		return 0, 0, errors.New("Termianl size height error, stty size return unexpected: " + string(outarr[0]))
-	}
-
-	width, err := strconv.Atoi(strings.Trim(outarr[1], " \n"))
+// isTTY reports whether stdout is attached to a terminal.
+func isTTY() bool {
+	info, err := os.Stdout.Stat()
 	if err != nil {
-		return 0, 0, errors.New("Terminal size width error, stty size return unexpected: " + string(outarr[1]))
-	}
-
-	return width, height, nil
-}
-
-// JsonHeading represents a cheatsheet heading definition. This is designed to be parsed from JSON.
-type JsonHeading struct {
-	Title    string   `json:"title"`
-	Commands []string `json:"commands"`
-}
-
-// JsonHeading represents a cheatsheet definition, of all headings. This is designed to be parsed from JSON.
-type JsonHeadings struct {
-	Headings []JsonHeading `json:"headings"`
-}
-
-// Width returns the width of this JsonHeading, which is the width of the longest command.
-func (h JsonHeading) Width() int {
-	max := len(h.Title)
-	for _, v := range h.Commands {
-		if len(v) > max {
-			max = len(v)
-		}
-	}
-	return max
-}
-
-// HeadingWidth returns the width which headings should be, which is the width of the widest heading
-func (hs JsonHeadings) HeadingWidth() int {
-	max := 0
-	for _, v := range hs.Headings {
-		if v.Width() > max {
-			max = v.Width()
-		}
+		return false
 	}
-	return max
+	return (info.Mode() & os.ModeCharDevice) != 0
 }
 
-// CommandsHeight is the height which commands should be, which is the number of commands in the heading with the most commands.
-func (hs JsonHeadings) CommandsHeight() int {
-	max := 0
-	for _, v := range hs.Headings {
-		if len(v.Commands) > max {
-			max = len(v.Commands)
-		}
-	}
-	return max
-}
-
-// RowHasCommands returns whether a given row has any commands.
-// This is used to not display blank lines, if all the headings in a given row have fewer than the tallest row.
-func (hs JsonHeadings) RowHasCommands(start, end, row int) bool {
-	for i := start; i < end; i++ {
-		heading := hs.Headings[i]
-		if len(heading.Commands) >= (row + 1) {
-			return true
+func main() {
+	if len(os.Args) >= 2 {
+		switch os.Args[1] {
+		case "list":
+			for _, name := range ListCheatsheets() {
+				fmt.Println(name)
+			}
+			return
+		case "get":
+			if len(os.Args) < 3 {
+				fmt.Println(`Usage: cman get <name>`)
+				return
+			}
+			if err := GetCheatsheet(os.Args[2]); err != nil {
+				fmt.Printf("get error: %v\n", err)
+			}
+			return
+		case "update":
+			if err := UpdateCheatsheets(); err != nil {
+				fmt.Printf("update error: %v\n", err)
+			}
+			return
 		}
 	}
-	return false
-}
-
-// PrintHeadings returns the heading string to be printed to the console, including ANSI color codes
-func (hs *JsonHeadings) PrintHeadings(start, end, width int) string {
-	if end > len(hs.Headings) {
-		end = len(hs.Headings)
-	}
-
-	var s string
-	for i := start; i != end; i++ {
-		heading := hs.Headings[i]
-		s += InverseColor()
-		s += heading.Title + strings.Repeat(" ", width-len(heading.Title)-1)
-		s += ResetColor()
-		s += ` `
-	}
 
-	commandsHeight := hs.CommandsHeight()
-	for j := 0; j != commandsHeight; j++ {
-		if !hs.RowHasCommands(start, end, j) {
-			continue
-		}
-		s += "\n"
-		for i := start; i < end; i++ {
-			heading := hs.Headings[i]
-			if len(heading.Commands) <= j {
-				s += strings.Repeat(" ", width)
-				continue
-			}
-			command := heading.Commands[j]
-			s += command + strings.Repeat(" ", width-len(command))
+	CheckForUpdates()
+
+	var interactive, fuzzy, headerFirst bool
+	format := "ansi"
+	widthOverride := 0
+	tag := ""
+	var args []string
+	for _, arg := range os.Args[1:] {
+		switch {
+		case arg == "-i":
+			interactive = true
+		case arg == "--fuzzy":
+			fuzzy = true
+		case arg == "--header-first":
+			headerFirst = true
+		case strings.HasPrefix(arg, "-format="):
+			format = strings.TrimPrefix(arg, "-format=")
+		case strings.HasPrefix(arg, "--width="):
+			widthOverride, _ = strconv.Atoi(strings.TrimPrefix(arg, "--width="))
+		case strings.HasPrefix(arg, "-tag="):
+			tag = strings.TrimPrefix(arg, "-tag=")
+		default:
+			args = append(args, arg)
 		}
 	}
 
-	return s
-}
-
-// PrintString returns a string for printing, of the headings, including ANSI color codes, formatted to the given width.
-func (hs *JsonHeadings) PrintString(width int) string {
-	var s string
-
-	headingWidth := hs.HeadingWidth() + 1
-	headingsPerLine := width / headingWidth // +1 because headings are separated
-	for i := 0; i < len(hs.Headings); i += headingsPerLine {
-		s += hs.PrintHeadings(i, i+headingsPerLine, headingWidth) + "\n"
+	renderer, ok := Renderers[format]
+	if !ok {
+		fmt.Println(`Unknown -format: ` + format)
+		return
 	}
-	return s
-}
 
-func main() {
-	if len(os.Args) < 2 {
-		fmt.Println(`Usage: cman <program>`)
+	if len(args) < 1 {
+		fmt.Println(`Usage: cman [-i] [--fuzzy] [-format=ansi|md|html|json|man] [--width=N] [--header-first] [-tag=<tag>] <program>`)
 		return
 	}
 
-	filename := os.Args[1] + `.json`
-
-	if _, err := os.Stat(filename); os.IsNotExist(err) {
-		fmt.Println(`Cheatsheet Man does not exist for ` + os.Args[1])
+	filename, err := FindCheatsheet(args[0])
+	if err != nil {
+		fmt.Println(err)
 		return
 	}
 
@@ -199,12 +98,31 @@ func main() {
 		fmt.Printf("JSON unmarshal error: %v\n", err)
 		return
 	}
+	jsonHeadings = jsonHeadings.FilterByTag(tag)
 
 	width, _, err := TerminalSize()
 	if err != nil {
 		fmt.Printf("Terminal size error: %v\n", err)
 		return
 	}
+	if widthOverride > 0 {
+		width = widthOverride
+	}
+
+	if interactive || (fuzzy && isTTY()) {
+		if err := RunInteractive(&jsonHeadings, width); err != nil {
+			fmt.Printf("Interactive mode error: %v\n", err)
+		}
+		return
+	}
+
+	if headerFirst {
+		legend := jsonHeadings.Legend(args[0])
+		if err := RunHeaderFirst(legend, renderer.Render(&jsonHeadings, width)); err != nil {
+			fmt.Printf("Header-first mode error: %v\n", err)
+		}
+		return
+	}
 
-	fmt.Println(jsonHeadings.PrintString(width))
+	fmt.Println(renderer.Render(&jsonHeadings, width))
 }
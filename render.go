@@ -0,0 +1,139 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"strings"
+)
+
+// Renderer turns a parsed cheatsheet into a string ready to print or pipe
+// elsewhere. Each format (ansi, markdown, html, json, man) implements this so
+// main can pick one via -format without knowing the details of any other.
+type Renderer interface {
+	Render(hs *JsonHeadings, width int) string
+}
+
+// Renderers maps the -format flag values to their Renderer implementation.
+var Renderers = map[string]Renderer{
+	"ansi": AnsiRenderer{},
+	"md":   MarkdownRenderer{},
+	"html": HTMLRenderer{},
+	"json": JSONRenderer{},
+	"man":  ManRenderer{},
+}
+
+// AnsiRenderer renders the columnar, colour-coded view used on an interactive
+// terminal. It defers to JsonHeadings.PrintString, which owns the shared
+// layout model (heading width, commands height, row-has-commands).
+type AnsiRenderer struct{}
+
+// Render implements Renderer.
+func (AnsiRenderer) Render(hs *JsonHeadings, width int) string {
+	return hs.PrintString(width)
+}
+
+// MarkdownRenderer renders each heading as a Markdown table of commands,
+// suitable for pasting into a wiki page.
+type MarkdownRenderer struct{}
+
+// Render implements Renderer.
+func (MarkdownRenderer) Render(hs *JsonHeadings, width int) string {
+	var s string
+	for _, heading := range hs.Headings {
+		s += "## " + heading.Title + "\n\n"
+		if heading.Description != "" {
+			s += heading.Description + "\n\n"
+		}
+		if len(heading.Commands) == 0 {
+			continue
+		}
+		s += "| Command | Note |\n| --- | --- |\n"
+		for _, command := range heading.Commands {
+			s += "| `" + markdownEscape(command.Command) + "` | " + markdownEscape(command.Note) + " |\n"
+		}
+		s += "\n"
+	}
+	return s
+}
+
+// markdownEscape escapes a pipe character, so a command or note containing
+// one doesn't get parsed as extra table columns.
+func markdownEscape(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+// HTMLRenderer renders a standalone HTML document with one section per
+// heading, tagged with CSS classes so the page can be restyled freely.
+type HTMLRenderer struct{}
+
+// Render implements Renderer.
+func (HTMLRenderer) Render(hs *JsonHeadings, width int) string {
+	s := "<!DOCTYPE html>\n<html>\n<head><meta charset=\"utf-8\"><title>cman</title></head>\n<body>\n"
+	for _, heading := range hs.Headings {
+		s += "  <section class=\"cman-heading\">\n"
+		s += "    <h2 class=\"cman-heading-title\">" + html.EscapeString(heading.Title) + "</h2>\n"
+		if heading.Description != "" {
+			s += "    <p class=\"cman-heading-description\">" + html.EscapeString(heading.Description) + "</p>\n"
+		}
+		s += "    <ul class=\"cman-commands\">\n"
+		for _, command := range heading.Commands {
+			s += "      <li class=\"cman-command\"><code>" + html.EscapeString(command.Command) + "</code>"
+			if command.Note != "" {
+				s += " <span class=\"cman-note\">" + html.EscapeString(command.Note) + "</span>"
+			}
+			s += "</li>\n"
+		}
+		s += "    </ul>\n"
+		s += "  </section>\n"
+	}
+	s += "</body>\n</html>\n"
+	return s
+}
+
+// JSONRenderer pretty-prints the parsed cheatsheet, useful for feeding into
+// other tooling.
+type JSONRenderer struct{}
+
+// Render implements Renderer.
+func (JSONRenderer) Render(hs *JsonHeadings, width int) string {
+	out, err := json.MarshalIndent(hs, "", "  ")
+	if err != nil {
+		return fmt.Sprintf("JSON render error: %v", err)
+	}
+	return string(out)
+}
+
+// ManRenderer renders a roff document, suitable for piping into `man -l -`.
+type ManRenderer struct{}
+
+// Render implements Renderer.
+func (ManRenderer) Render(hs *JsonHeadings, width int) string {
+	s := ".TH CMAN 1\n"
+	for _, heading := range hs.Headings {
+		s += ".SH " + roffEscape(heading.Title) + "\n"
+		if heading.Description != "" {
+			s += roffEscape(heading.Description) + "\n"
+		}
+		for _, command := range heading.Commands {
+			s += ".TP\n.B " + roffEscape(command.Command) + "\n"
+			if command.Note != "" {
+				s += roffEscape(command.Note) + "\n"
+			}
+		}
+	}
+	return s
+}
+
+// roffEscape escapes characters that are meaningful to roff, so commands
+// containing them (e.g. a leading "-") render correctly.
+func roffEscape(s string) string {
+	out := ""
+	for _, r := range s {
+		if r == '-' || r == '\\' {
+			out += `\`
+		}
+		out += string(r)
+	}
+	return out
+}
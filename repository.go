@@ -0,0 +1,350 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultRemoteIndexURL is the community cheatsheet index consulted by
+// `cman get`/`cman update` when $CMAN_REMOTE_URL is not set.
+const DefaultRemoteIndexURL = "https://raw.githubusercontent.com/robert-butts/cman-cheatsheets/main"
+
+// updateCheckInterval is how often the startup notifier is allowed to hit the
+// network for a fresh manifest.
+const updateCheckInterval = 24 * time.Hour
+
+// downloadHTTPClient is used for `cman get`/`cman update`, an explicit,
+// user-requested download: it gets a generous timeout so a slow connection
+// still has a chance to finish.
+var downloadHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// updateCheckHTTPClient is used only by the opportunistic startup notifier,
+// which must never make an ordinary `cman <program>` invocation feel slow:
+// a couple of seconds is enough to catch a manifest on a healthy network and
+// give up quickly on a slow or blackholed one.
+var updateCheckHTTPClient = &http.Client{Timeout: 2 * time.Second}
+
+// SearchPaths returns the ordered list of directories cman looks in for
+// cheatsheets: the current directory first (so `cman foo` still finds
+// ./foo.json as before), then any directories in $CMAN_PATH (colon
+// separated), then $XDG_DATA_HOME/cman (or ~/.local/share/cman), then
+// /usr/share/cman.
+func SearchPaths() []string {
+	paths := []string{"."}
+
+	if cmanPath := os.Getenv("CMAN_PATH"); cmanPath != "" {
+		paths = append(paths, strings.Split(cmanPath, ":")...)
+	}
+
+	if xdg := os.Getenv("XDG_DATA_HOME"); xdg != "" {
+		paths = append(paths, filepath.Join(xdg, "cman"))
+	} else if home, err := os.UserHomeDir(); err == nil {
+		paths = append(paths, filepath.Join(home, ".local", "share", "cman"))
+	}
+
+	paths = append(paths, "/usr/share/cman")
+
+	return paths
+}
+
+// FindCheatsheet searches SearchPaths for a cheatsheet file for name,
+// returning the first match.
+func FindCheatsheet(name string) (string, error) {
+	filename := name + ".json"
+	for _, dir := range SearchPaths() {
+		candidate := filepath.Join(dir, filename)
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate, nil
+		}
+	}
+	return "", errors.New("Cheatsheet Man does not exist for " + name)
+}
+
+// ListCheatsheets enumerates every cheatsheet found across SearchPaths, de-duplicating
+// by name and preferring the first path a name is found in.
+func ListCheatsheets() []string {
+	seen := map[string]bool{}
+	var names []string
+	for _, dir := range SearchPaths() {
+		entries, err := ioutil.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+				continue
+			}
+			name := strings.TrimSuffix(entry.Name(), ".json")
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// installDir returns the directory `cman get`/`cman update` write into: the
+// $XDG_DATA_HOME/cman (or ~/.local/share/cman) entry from SearchPaths.
+func installDir() (string, error) {
+	if xdg := os.Getenv("XDG_DATA_HOME"); xdg != "" {
+		return filepath.Join(xdg, "cman"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".local", "share", "cman"), nil
+}
+
+// remoteIndexURL returns the configured remote index, or DefaultRemoteIndexURL.
+func remoteIndexURL() string {
+	if url := os.Getenv("CMAN_REMOTE_URL"); url != "" {
+		return url
+	}
+	return DefaultRemoteIndexURL
+}
+
+// Manifest describes the cheatsheets available at a remote index: their
+// SHA-256 hashes (for verifying downloads) and when the index was last
+// published.
+type Manifest struct {
+	Timestamp   int64             `json:"timestamp"`
+	Cheatsheets map[string]string `json:"cheatsheets"` // name -> sha256 hex
+}
+
+// fetchManifest downloads and parses the manifest.json at the front of the
+// remote index.
+func fetchManifest(client *http.Client) (*Manifest, error) {
+	resp, err := client.Get(remoteIndexURL() + "/manifest.json")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("manifest fetch failed: %s", resp.Status)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(body, &manifest); err != nil {
+		return nil, err
+	}
+	return &manifest, nil
+}
+
+// sanitizeCheatsheetName rejects any remote-supplied name that isn't a bare
+// file stem, so it can't be used to escape installDir via path traversal
+// (e.g. "../../../../tmp/evil") when building a fetch URL or a local path.
+func sanitizeCheatsheetName(name string) error {
+	if name == "" || name != filepath.Base(name) || name == "." || name == ".." {
+		return errors.New("invalid cheatsheet name: " + name)
+	}
+	return nil
+}
+
+// fetchCheatsheet downloads a single cheatsheet and verifies it against the
+// SHA-256 hash recorded in the manifest before returning its contents.
+func fetchCheatsheet(client *http.Client, manifest *Manifest, name string) ([]byte, error) {
+	if err := sanitizeCheatsheetName(name); err != nil {
+		return nil, err
+	}
+
+	expected, ok := manifest.Cheatsheets[name]
+	if !ok {
+		return nil, errors.New("no such cheatsheet in remote index: " + name)
+	}
+
+	resp, err := client.Get(remoteIndexURL() + "/" + name + ".json")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch of %s failed: %s", name, resp.Status)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(body)
+	if hex.EncodeToString(sum[:]) != expected {
+		return nil, errors.New("checksum mismatch for " + name + ", refusing to write it")
+	}
+
+	return body, nil
+}
+
+// GetCheatsheet downloads name from the remote index and installs it into
+// installDir, verifying its checksum against the manifest first.
+func GetCheatsheet(name string) error {
+	manifest, err := fetchManifest(downloadHTTPClient)
+	if err != nil {
+		return err
+	}
+
+	body, err := fetchCheatsheet(downloadHTTPClient, manifest, name)
+	if err != nil {
+		return err
+	}
+
+	dir, err := installDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(filepath.Join(dir, name+".json"), body, 0644)
+}
+
+// UpdateCheatsheets re-downloads every cheatsheet named in the remote
+// manifest, verifying each against its checksum before writing it.
+func UpdateCheatsheets() error {
+	manifest, err := fetchManifest(downloadHTTPClient)
+	if err != nil {
+		return err
+	}
+
+	dir, err := installDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	for name := range manifest.Cheatsheets {
+		body, err := fetchCheatsheet(downloadHTTPClient, manifest, name)
+		if err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, name+".json"), body, 0644); err != nil {
+			return err
+		}
+	}
+
+	return writeUpdateState(manifest.Timestamp)
+}
+
+// updateState is throttling state persisted to disk: when we last checked the
+// remote manifest, and what timestamp it reported.
+type updateState struct {
+	LastChecked     int64 `json:"last_checked"`
+	RemoteTimestamp int64 `json:"remote_timestamp"`
+}
+
+// statePath returns where the throttling state file for the update notifier lives.
+func statePath() (string, error) {
+	dir, err := installDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "state.json"), nil
+}
+
+// readUpdateState loads the persisted throttling state, if any.
+func readUpdateState() (*updateState, error) {
+	path, err := statePath()
+	if err != nil {
+		return nil, err
+	}
+	body, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var state updateState
+	if err := json.Unmarshal(body, &state); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+// writeUpdateState persists the throttling state, recording now as the last
+// check time and remoteTimestamp as the manifest's reported publish time.
+func writeUpdateState(remoteTimestamp int64) error {
+	dir, err := installDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	state := updateState{LastChecked: time.Now().Unix(), RemoteTimestamp: remoteTimestamp}
+	body, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	path, err := statePath()
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, body, 0644)
+}
+
+// CheckForUpdates prints a colorized banner if the remote index reports a
+// publish time newer than the one recorded in the on-disk state file (by the
+// last `cman update` or the last successful check, so a given publish is
+// announced once), throttled to once per
+// updateCheckInterval via the on-disk state file. It only runs when stdout is
+// a terminal, so it never injects a banner line ahead of output that's being
+// piped or redirected. Network and disk errors are swallowed: this is a
+// best-effort notifier, not a hard requirement to run cman.
+func CheckForUpdates() {
+	if !isTTY() {
+		return
+	}
+
+	state, err := readUpdateState()
+	if err == nil && time.Since(time.Unix(state.LastChecked, 0)) < updateCheckInterval {
+		return
+	}
+
+	manifest, err := fetchManifest(updateCheckHTTPClient)
+	if err != nil {
+		// Record the attempt even though it failed, so an offline or
+		// blackholed run doesn't make every subsequent invocation eat the
+		// same network timeout until updateCheckInterval would have elapsed
+		// anyway.
+		previousTimestamp := int64(0)
+		if state != nil {
+			previousTimestamp = state.RemoteTimestamp
+		}
+		writeUpdateState(previousTimestamp)
+		return
+	}
+
+	previousTimestamp := int64(0)
+	if state != nil {
+		previousTimestamp = state.RemoteTimestamp
+	}
+
+	if manifest.Timestamp > previousTimestamp {
+		days := int(time.Since(time.Unix(manifest.Timestamp, 0)).Hours() / 24)
+		fmt.Println(Color(Black, Green, true) + fmt.Sprintf("newer cheatsheets available %d days ago", days) + ResetColor())
+	}
+
+	writeUpdateState(manifest.Timestamp)
+}
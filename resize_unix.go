@@ -0,0 +1,18 @@
+//go:build unix
+
+package main
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// watchResize returns a channel that receives a value whenever the
+// controlling terminal is resized (SIGWINCH). Windows has no equivalent
+// signal, so this is only implemented here.
+func watchResize() <-chan os.Signal {
+	resized := make(chan os.Signal, 1)
+	signal.Notify(resized, syscall.SIGWINCH)
+	return resized
+}
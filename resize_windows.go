@@ -0,0 +1,11 @@
+//go:build windows
+
+package main
+
+import "os"
+
+// watchResize returns a channel that never fires: Windows consoles have no
+// SIGWINCH equivalent, so on Windows the TUI keeps the width it started with.
+func watchResize() <-chan os.Signal {
+	return make(chan os.Signal)
+}
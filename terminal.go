@@ -0,0 +1,38 @@
+package main
+
+import (
+	"os"
+	"strconv"
+)
+
+// TerminalSize returns the width and height of the controlling terminal. It
+// prefers a direct syscall against stdout, falls back to the $COLUMNS/$LINES
+// environment variables (set by most shells even for non-interactive
+// children), and finally defaults to 80x24 so that e.g. `cman foo | less`
+// still produces sensible output when stdout isn't a tty at all.
+func TerminalSize() (int, int, error) {
+	if width, height, err := terminalSizeSyscall(); err == nil && width > 0 && height > 0 {
+		return width, height, nil
+	}
+
+	if width, height, ok := terminalSizeFromEnv(); ok {
+		return width, height, nil
+	}
+
+	return 80, 24, nil
+}
+
+// terminalSizeFromEnv reads $COLUMNS and $LINES, as exported by most shells.
+func terminalSizeFromEnv() (width int, height int, ok bool) {
+	columns, err := strconv.Atoi(os.Getenv("COLUMNS"))
+	if err != nil || columns <= 0 {
+		return 0, 0, false
+	}
+
+	lines, err := strconv.Atoi(os.Getenv("LINES"))
+	if err != nil || lines <= 0 {
+		return 0, 0, false
+	}
+
+	return columns, lines, true
+}
@@ -0,0 +1,25 @@
+//go:build unix
+
+package main
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// unixWinsize mirrors the kernel's struct winsize, as filled in by TIOCGWINSZ.
+type unixWinsize struct {
+	Row, Col, Xpixel, Ypixel uint16
+}
+
+// terminalSizeSyscall asks the kernel for stdout's window size via the
+// TIOCGWINSZ ioctl, the same call `stty size` itself shells out to.
+func terminalSizeSyscall() (int, int, error) {
+	var ws unixWinsize
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, os.Stdout.Fd(), uintptr(syscall.TIOCGWINSZ), uintptr(unsafe.Pointer(&ws)))
+	if errno != 0 {
+		return 0, 0, errno
+	}
+	return int(ws.Col), int(ws.Row), nil
+}
@@ -0,0 +1,39 @@
+//go:build windows
+
+package main
+
+import (
+	"errors"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	kernel32                       = syscall.NewLazyDLL("kernel32.dll")
+	procGetConsoleScreenBufferInfo = kernel32.NewProc("GetConsoleScreenBufferInfo")
+)
+
+// consoleScreenBufferInfo mirrors the Win32 CONSOLE_SCREEN_BUFFER_INFO struct.
+type consoleScreenBufferInfo struct {
+	size              [2]int16
+	cursorPosition    [2]int16
+	attributes        uint16
+	window            [4]int16 // left, top, right, bottom
+	maximumWindowSize [2]int16
+}
+
+// terminalSizeSyscall asks the Windows console for the size of stdout's
+// visible window, taken from the window rectangle that
+// GetConsoleScreenBufferInfo reports (not the larger buffer size).
+func terminalSizeSyscall() (int, int, error) {
+	var info consoleScreenBufferInfo
+	ret, _, err := procGetConsoleScreenBufferInfo.Call(os.Stdout.Fd(), uintptr(unsafe.Pointer(&info)))
+	if ret == 0 {
+		return 0, 0, errors.New("GetConsoleScreenBufferInfo failed: " + err.Error())
+	}
+
+	width := int(info.window[2]-info.window[0]) + 1
+	height := int(info.window[3]-info.window[1]) + 1
+	return width, height, nil
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// clearScreen returns the ANSI sequence to clear the screen and home the cursor.
+func clearScreen() string {
+	return "\033[2J\033[H"
+}
+
+// buildFuzzyEntries flattens every command in hs into a list of fuzzy candidates,
+// one per command, tagged with the heading it came from.
+func buildFuzzyEntries(hs *JsonHeadings) []fuzzyEntry {
+	var entries []fuzzyEntry
+	for _, heading := range hs.Headings {
+		for _, cmd := range heading.Commands {
+			entries = append(entries, fuzzyEntry{Heading: heading.Title, Display: cmd.Command, Command: cmd.Command})
+		}
+	}
+	return entries
+}
+
+// highlightMatches wraps the runes of s at the given positions in inverse video,
+// returning the decorated string. Positions must be sorted ascending.
+func highlightMatches(s string, positions []int) string {
+	if len(positions) == 0 {
+		return s
+	}
+	runes := []rune(s)
+	posSet := make(map[int]bool, len(positions))
+	for _, p := range positions {
+		posSet[p] = true
+	}
+
+	var b strings.Builder
+	inMatch := false
+	for i, r := range runes {
+		if posSet[i] && !inMatch {
+			b.WriteString(InverseColor())
+			inMatch = true
+		} else if !posSet[i] && inMatch {
+			b.WriteString(ResetColor())
+			inMatch = false
+		}
+		b.WriteRune(r)
+	}
+	if inMatch {
+		b.WriteString(ResetColor())
+	}
+	return b.String()
+}
+
+// enterRawMode puts the controlling tty into raw, no-echo mode so the TUI can
+// read individual keystrokes, and returns a function that restores it. It
+// opens /dev/tty directly and feeds it to `stty` as stdin/stdout rather than
+// naming the device via a flag, since BSD/macOS stty takes -f and GNU stty
+// (most Linux) takes -F for that.
+func enterRawMode() (restore func(), err error) {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		tty = os.Stdin
+	}
+
+	stty := func(args ...string) error {
+		cmd := exec.Command("stty", args...)
+		cmd.Stdin = tty
+		cmd.Stdout = tty
+		cmd.Stderr = os.Stderr
+		return cmd.Run()
+	}
+
+	if err := stty("raw", "-echo"); err != nil {
+		return nil, err
+	}
+
+	return func() {
+		stty("sane")
+	}, nil
+}
+
+// RunInteractive drives the full-screen fuzzy-search TUI for hs. It blocks
+// until the user selects a command (which is copied to the clipboard) or
+// quits with Esc.
+func RunInteractive(hs *JsonHeadings, initialWidth int) error {
+	restore, err := enterRawMode()
+	if err != nil {
+		return err
+	}
+	defer restore()
+
+	width := initialWidth
+	resized := watchResize()
+	go func() {
+		for range resized {
+			if w, _, err := TerminalSize(); err == nil {
+				width = w
+			}
+		}
+	}()
+
+	entries := buildFuzzyEntries(hs)
+	query := ""
+	cursor := 0
+
+	redraw := func() {
+		var s strings.Builder
+		s.WriteString(clearScreen())
+		s.WriteString(InverseColor())
+		s.WriteString("cman> " + query)
+		s.WriteString(ResetColor())
+		s.WriteString("\n")
+
+		if query == "" {
+			s.WriteString(hs.PrintString(width))
+		} else {
+			results := FuzzyFilter(entries, query)
+			if cursor >= len(results) {
+				cursor = len(results) - 1
+			}
+			if cursor < 0 {
+				cursor = 0
+			}
+			for i, r := range results {
+				line := r.Heading + ": " + highlightMatches(r.Display, r.Positions)
+				if i == cursor {
+					s.WriteString("> " + line + "\n")
+				} else {
+					s.WriteString("  " + line + "\n")
+				}
+			}
+		}
+		fmt.Print(s.String())
+	}
+
+	redraw()
+
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		b, err := reader.ReadByte()
+		if err != nil {
+			return err
+		}
+
+		results := FuzzyFilter(entries, query)
+
+		switch b {
+		case 27: // Esc, or the start of an arrow-key escape sequence
+			// An arrow key arrives as one burst ("\033[A"), so nothing else
+			// buffered after the Esc byte means Esc was pressed on its own.
+			if reader.Buffered() == 0 {
+				return nil // bare Esc: quit
+			}
+			next, err := reader.ReadByte()
+			if err != nil || next != '[' {
+				return nil // bare Esc: quit
+			}
+			arrow, err := reader.ReadByte()
+			if err != nil {
+				return nil
+			}
+			switch arrow {
+			case 'A': // up
+				if cursor > 0 {
+					cursor--
+				}
+			case 'B': // down
+				if cursor < len(results)-1 {
+					cursor++
+				}
+			}
+		case 14: // Ctrl-N
+			if cursor < len(results)-1 {
+				cursor++
+			}
+		case 16: // Ctrl-P
+			if cursor > 0 {
+				cursor--
+			}
+		case '\r', '\n':
+			if cursor >= 0 && cursor < len(results) {
+				restore()
+				return CopyToClipboard(results[cursor].Command)
+			}
+			return nil
+		case 127, 8: // backspace
+			if len(query) > 0 {
+				query = query[:len(query)-1]
+				cursor = 0
+			}
+		case 3: // Ctrl-C
+			return nil
+		default:
+			if b >= 32 && b < 127 {
+				query += string(b)
+				cursor = 0
+			}
+		}
+
+		redraw()
+	}
+}